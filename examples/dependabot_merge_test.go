@@ -0,0 +1,40 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestMergeDependabotUpdate guards against losing the compose entry for a kind's
+// directory prefix, which generateDependabotUpdates silently did before this fix.
+func TestMergeDependabotUpdate(t *testing.T) {
+	existing := Updates{
+		{Directory: "/"},
+		{Directory: "/modules/compose"},
+		{Directory: "/modules/mongodb"},
+		{Directory: "/examples/nginx"},
+	}
+
+	got := mergeDependabotUpdate(existing, "/modules", Update{Directory: "/modules/zzz"})
+
+	dirs := make([]string, len(got))
+	for i, u := range got {
+		dirs[i] = u.Directory
+	}
+
+	if !slices.Contains(dirs, "/modules/compose") {
+		t.Fatalf("compose entry was dropped: %v", dirs)
+	}
+	if !slices.Contains(dirs, "/modules/zzz") {
+		t.Fatalf("new entry was not added: %v", dirs)
+	}
+	if !slices.Contains(dirs, "/") || !slices.Contains(dirs, "/examples/nginx") {
+		t.Fatalf("entries outside the /modules prefix were disturbed: %v", dirs)
+	}
+
+	composeIdx := slices.Index(dirs, "/modules/compose")
+	mongodbIdx := slices.Index(dirs, "/modules/mongodb")
+	if composeIdx > mongodbIdx {
+		t.Fatalf("compose entry should stay first within its kind, got order %v", dirs)
+	}
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// mkdocs nav indices for the "modules" and "examples" sections of the top-level nav,
+// see mkdocs.yml.
+const (
+	modulesNavIndex  = 2
+	examplesNavIndex = 3
+)
+
+// Generator renders the template subtree for a given kind of integration (module or
+// example) into its own output root, and keeps the generated mkdocs nav and
+// dependabot config in sync with what it writes. New kinds (e.g. testing helpers)
+// can be added by providing another implementation instead of adding kind-specific
+// cases to the file-routing switch in outputPath.
+type Generator interface {
+	// Generate renders every template for example into the generator's output
+	// root and updates the mkdocs nav and dependabot config to reference it.
+	Generate(example Example, rootDir string) error
+	// List returns the names of the integrations already generated under this
+	// generator's output root.
+	List(rootDir string) ([]string, error)
+	// Remove deletes a previously generated integration and its docs/CI/dependabot
+	// entries.
+	Remove(rootDir string, name string) error
+	// TemplateDir returns the _template subtree this generator renders from, so
+	// that callers can load its vars.toml before calling Generate.
+	TemplateDir() string
+	// Verify re-renders name's templates from its stamp file and reports whether
+	// the on-disk files have drifted from them, fixing them in place if fix is
+	// true.
+	Verify(rootDir string, name string, fix bool) (bool, error)
+	// VerifyMkdocs reports whether this kind's mkdocs nav section is out of sync
+	// with the integrations actually on disk, fixing it in place if fix is true.
+	VerifyMkdocs(rootDir string, fix bool) (bool, error)
+	// VerifyDependabot reports whether dependabot.yml is missing an update entry
+	// for any integration actually on disk, fixing it in place if fix is true.
+	VerifyDependabot(rootDir string, fix bool) (bool, error)
+}
+
+// baseGenerator implements the template expansion and file-routing shared by every
+// Generator. Kind-specific knobs (output root, template subtree, docs nav slot,
+// dependabot directory prefix) are supplied by the embedding type.
+type baseGenerator struct {
+	// kind is this generator's plural name: it names the output root
+	// (e.g. "modules"), the docs directory (docs/modules) and the _template
+	// subtree (_template/modules) it renders from.
+	kind string
+	// singular is used to build per-file suffixes, e.g. the "-module" in the
+	// generated GitHub workflow file name.
+	singular string
+	// mkdocsNavIndex is the index of this kind's section within the mkdocs nav.
+	mkdocsNavIndex int
+	// dependabotDirPrefix is the "/modules" or "/examples" prefix used when
+	// registering a generated integration's package-ecosystem directory.
+	dependabotDirPrefix string
+}
+
+func (g *baseGenerator) templateDir() string {
+	return filepath.Join("_template", g.kind)
+}
+
+// TemplateDir returns the _template subtree this generator renders from.
+func (g *baseGenerator) TemplateDir() string {
+	return g.templateDir()
+}
+
+func (g *baseGenerator) outputDir(rootDir string) string {
+	return filepath.Join(rootDir, g.kind)
+}
+
+func (g *baseGenerator) docsDir(rootDir string) string {
+	return filepath.Join(rootDir, "docs", g.kind)
+}
+
+// funcMap builds the template.FuncMap used to render example's templates.
+func (g *baseGenerator) funcMap(example Example) template.FuncMap {
+	return template.FuncMap{
+		"ToLower":      func() string { return example.Lower() },
+		"Title":        func() string { return example.Title() },
+		"ToLowerTitle": func() string { return example.LowerTitle() },
+		"codeinclude":  func(s string) template.HTML { return template.HTML(s) }, // escape HTML comments for codeinclude
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"title":        func(s string) string { return cases.Title(language.Und, cases.NoLower).String(s) },
+		"snake":        toSnakeCase,
+		"kebab":        toKebabCase,
+		"camel":        toCamelCase,
+	}
+}
+
+// renderTemplate renders the _template/<kind>/<tmpl>.tmpl file against example and
+// returns the rendered bytes, without touching disk. It is shared by Generate, which
+// writes the result to the example's output path, and Verify, which diffs it against
+// the file already on disk.
+func (g *baseGenerator) renderTemplate(example Example, tmpl string) ([]byte, error) {
+	name := tmpl + ".tmpl"
+	t, err := template.New(name).Funcs(g.funcMap(example)).ParseFiles(filepath.Join(g.templateDir(), name))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, example); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// outputPath returns the path a rendered tmpl is written to for the given example
+// name, routing docs, CI workflow and tools.go files into their own directories.
+func (g *baseGenerator) outputPath(rootDir string, exampleLower string, tmpl string) string {
+	outputDir := g.outputDir(rootDir)
+
+	switch {
+	case strings.EqualFold(tmpl, "docs_example.md"):
+		// docs file will go into the kind's docs directory
+		return filepath.Join(g.docsDir(rootDir), exampleLower+".md")
+	case strings.EqualFold(tmpl, "ci.yml"):
+		// GitHub workflow file will go into the .github/workflows directory
+		return filepath.Join(rootDir, ".github", "workflows", exampleLower+"-"+g.singular+".yml")
+	case strings.EqualFold(tmpl, "tools.go"):
+		// tools.go file will go into the tools package
+		return filepath.Join(outputDir, exampleLower, "tools", tmpl)
+	default:
+		return filepath.Join(outputDir, exampleLower, strings.ReplaceAll(tmpl, "example", exampleLower))
+	}
+}
+
+// Generate renders every template under the generator's _template subtree into its
+// output root, stamps the example's inputs for later verification, then regenerates
+// the mkdocs nav and dependabot config.
+func (g *baseGenerator) Generate(example Example, rootDir string) error {
+	if err := example.Validate(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(g.outputDir(rootDir), 0700); err != nil {
+		return err
+	}
+
+	exampleLower := example.Lower()
+
+	for _, tmpl := range templates {
+		rendered, err := g.renderTemplate(example, tmpl)
+		if err != nil {
+			return err
+		}
+
+		exampleFilePath := g.outputPath(rootDir, exampleLower, tmpl)
+
+		if err := os.MkdirAll(filepath.Dir(exampleFilePath), 0777); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(exampleFilePath, rendered, 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := writeStamp(filepath.Join(g.outputDir(rootDir), exampleLower), example); err != nil {
+		return err
+	}
+
+	if err := g.generateMkdocs(rootDir, exampleLower); err != nil {
+		return err
+	}
+
+	if err := g.generateDependabotUpdates(rootDir, exampleLower); err != nil {
+		return err
+	}
+
+	fmt.Println("Please go to", filepath.Join(g.kind, exampleLower), "directory and execute 'go mod tidy' to synchronize the dependencies")
+	fmt.Println("Commit the modified files and submit a pull request to include them into the project")
+	fmt.Println("Thanks!")
+	return nil
+}
+
+// Verify re-renders every template for the already-generated integration name and
+// reports whether the on-disk files have drifted from what the templates would now
+// produce. Drift inside a //testcontainers:generated-keep region is ignored. If fix
+// is true, drifted files are rewritten in place.
+//
+// The Example used to re-render comes from the integration's generated.toml stamp
+// if one was written, or is otherwise inferred from its example_test.go for
+// integrations that predate the stamp. If neither is available, name is skipped
+// (reported, not treated as drift) rather than aborting the whole verify run.
+func (g *baseGenerator) Verify(rootDir string, name string, fix bool) (bool, error) {
+	exampleDir := filepath.Join(g.outputDir(rootDir), name)
+
+	example, found, err := readStamp(exampleDir)
+	if err != nil {
+		return false, fmt.Errorf("could not read stamp for %s: %w", name, err)
+	}
+
+	if !found {
+		example, err = inferExampleFromTest(exampleDir, name)
+		if err != nil {
+			fmt.Printf("%s: skipping verify: %v\n", exampleDir, err)
+			return false, nil
+		}
+	}
+
+	drifted := false
+
+	for _, tmpl := range templates {
+		rendered, err := g.renderTemplate(example, tmpl)
+		if err != nil {
+			return false, err
+		}
+
+		path := g.outputPath(rootDir, name, tmpl)
+
+		existing, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			fmt.Printf("%s: missing\n", path)
+			drifted = true
+		case err != nil:
+			return false, err
+		default:
+			rendered = applyKeepRegions(existing, rendered)
+			if !bytes.Equal(existing, rendered) {
+				fmt.Printf("%s: out of sync with its template\n", path)
+				drifted = true
+			} else {
+				continue
+			}
+		}
+
+		if fix {
+			if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+				return false, err
+			}
+			if err := os.WriteFile(path, rendered, 0644); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return drifted, nil
+}
+
+// List returns the names of the integrations already generated under this
+// generator's output root.
+func (g *baseGenerator) List(rootDir string) ([]string, error) {
+	entries, err := os.ReadDir(g.outputDir(rootDir))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		// skip the _template subtree, which lives alongside the generated examples
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), "_") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// Remove deletes a previously generated integration's source, docs and CI workflow,
+// then drops it from the mkdocs nav and dependabot config.
+func (g *baseGenerator) Remove(rootDir string, name string) error {
+	nameLower := strings.ToLower(name)
+
+	paths := []string{
+		filepath.Join(g.outputDir(rootDir), nameLower),
+		filepath.Join(g.docsDir(rootDir), nameLower+".md"),
+		filepath.Join(rootDir, ".github", "workflows", nameLower+"-"+g.singular+".yml"),
+	}
+
+	for _, path := range paths {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	mkdocsConfig, err := readMkdocsConfig(rootDir)
+	if err != nil {
+		return err
+	}
+
+	nav := mkdocsConfig.Nav[g.mkdocsNavIndex].Examples
+	filtered := make([]string, 0, len(nav))
+	for _, entry := range nav {
+		if entry != g.kind+"/"+nameLower+".md" {
+			filtered = append(filtered, entry)
+		}
+	}
+	mkdocsConfig.Nav[g.mkdocsNavIndex].Examples = filtered
+
+	if err := writeMkdocsConfig(rootDir, mkdocsConfig); err != nil {
+		return err
+	}
+
+	dependabotConfig, err := readDependabotConfig(rootDir)
+	if err != nil {
+		return err
+	}
+
+	updates := make(Updates, 0, len(dependabotConfig.Updates))
+	for _, update := range dependabotConfig.Updates {
+		if update.Directory != g.dependabotDirPrefix+"/"+nameLower {
+			updates = append(updates, update)
+		}
+	}
+	dependabotConfig.Updates = updates
+
+	return writeDependabotConfig(rootDir, dependabotConfig)
+}
+
+// VerifyMkdocs reports whether the g.kind section of the mkdocs nav is out of sync
+// with the integrations actually present under g.outputDir, fixing it in place if
+// fix is true. This catches the common "new example added but mkdocs nav wasn't
+// regenerated" mistake.
+func (g *baseGenerator) VerifyMkdocs(rootDir string, fix bool) (bool, error) {
+	names, err := g.List(rootDir)
+	if err != nil {
+		return false, err
+	}
+
+	mkdocsConfig, err := readMkdocsConfig(rootDir)
+	if err != nil {
+		return false, err
+	}
+
+	want := make([]string, len(names))
+	for i, name := range names {
+		want[i] = g.kind + "/" + name + ".md"
+	}
+	sort.Strings(want)
+	want = append([]string{g.kind + "/index.md"}, want...)
+
+	got := mkdocsConfig.Nav[g.mkdocsNavIndex].Examples
+
+	if slices.Equal(got, want) {
+		return false, nil
+	}
+
+	fmt.Printf("mkdocs.yml: %s nav is out of sync with %s/\n", g.kind, g.kind)
+
+	if fix {
+		mkdocsConfig.Nav[g.mkdocsNavIndex].Examples = want
+		if err := writeMkdocsConfig(rootDir, mkdocsConfig); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// VerifyDependabot reports whether dependabot.yml is missing an update entry for
+// any integration actually present under g.outputDir, fixing it in place if fix is
+// true.
+func (g *baseGenerator) VerifyDependabot(rootDir string, fix bool) (bool, error) {
+	names, err := g.List(rootDir)
+	if err != nil {
+		return false, err
+	}
+
+	dependabotConfig, err := readDependabotConfig(rootDir)
+	if err != nil {
+		return false, err
+	}
+
+	have := make(map[string]bool, len(dependabotConfig.Updates))
+	for _, update := range dependabotConfig.Updates {
+		if strings.HasPrefix(update.Directory, g.dependabotDirPrefix+"/") {
+			have[strings.TrimPrefix(update.Directory, g.dependabotDirPrefix+"/")] = true
+		}
+	}
+
+	drifted := false
+
+	for _, name := range names {
+		if name == "compose" || have[name] {
+			continue
+		}
+
+		fmt.Printf("dependabot.yml: missing update entry for %s/%s\n", g.kind, name)
+		drifted = true
+
+		if fix {
+			if err := g.generateDependabotUpdates(rootDir, name); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return drifted, nil
+}
+
+func (g *baseGenerator) generateDependabotUpdates(rootDir string, exampleLower string) error {
+	dependabotConfig, err := readDependabotConfig(rootDir)
+	if err != nil {
+		return err
+	}
+
+	newUpdate := NewUpdate(exampleLower)
+	newUpdate.Directory = g.dependabotDirPrefix + "/" + exampleLower
+
+	dependabotConfig.Updates = mergeDependabotUpdate(dependabotConfig.Updates, g.dependabotDirPrefix, newUpdate)
+
+	return writeDependabotConfig(rootDir, dependabotConfig)
+}
+
+// mergeDependabotUpdate inserts newUpdate among the updates under prefix (e.g.
+// "/modules"), keeping that prefix's compose entry first and the rest sorted, while
+// leaving every update outside prefix untouched. It is the pure half of
+// generateDependabotUpdates, split out so the merge logic can be unit tested without
+// reading or writing dependabot.yml.
+func mergeDependabotUpdate(updates Updates, prefix string, newUpdate Update) Updates {
+	var composeUpdate *Update
+	kindUpdates := make(Updates, 0, len(updates))
+	otherUpdates := make(Updates, 0, len(updates))
+
+	for _, update := range updates {
+		switch {
+		case update.Directory == prefix+"/compose":
+			u := update
+			composeUpdate = &u
+		case strings.HasPrefix(update.Directory, prefix+"/"):
+			kindUpdates = append(kindUpdates, update)
+		default:
+			otherUpdates = append(otherUpdates, update)
+		}
+	}
+
+	kindUpdates = append(kindUpdates, newUpdate)
+	sort.Sort(kindUpdates)
+
+	if composeUpdate != nil {
+		kindUpdates = append(Updates{*composeUpdate}, kindUpdates...)
+	}
+
+	return append(otherUpdates, kindUpdates...)
+}
+
+func (g *baseGenerator) generateMkdocs(rootDir string, exampleLower string) error {
+	mkdocsConfig, err := readMkdocsConfig(rootDir)
+	if err != nil {
+		return err
+	}
+
+	mkdocsExamplesNav := mkdocsConfig.Nav[g.mkdocsNavIndex].Examples
+
+	// make sure the index.md is the first element in the list of examples in the nav
+	examplesNav := make([]string, 0, len(mkdocsExamplesNav))
+
+	for _, exampleNav := range mkdocsExamplesNav {
+		// filter out the index.md file
+		if !strings.HasSuffix(exampleNav, "index.md") {
+			examplesNav = append(examplesNav, exampleNav)
+		}
+	}
+
+	examplesNav = append(examplesNav, g.kind+"/"+exampleLower+".md")
+	sort.Strings(examplesNav)
+
+	// prepend the index.md file
+	examplesNav = append([]string{g.kind + "/index.md"}, examplesNav...)
+
+	mkdocsConfig.Nav[g.mkdocsNavIndex].Examples = examplesNav
+
+	return writeMkdocsConfig(rootDir, mkdocsConfig)
+}
+
+// ModuleGenerator generates first-class integrations under modules/, with their own
+// public API package, docs nav section and dependabot directory prefix.
+type ModuleGenerator struct {
+	baseGenerator
+}
+
+// NewModuleGenerator builds the Generator used by the "new module" subcommand.
+func NewModuleGenerator() *ModuleGenerator {
+	return &ModuleGenerator{
+		baseGenerator{
+			kind:                "modules",
+			singular:            "module",
+			mkdocsNavIndex:      modulesNavIndex,
+			dependabotDirPrefix: "/modules",
+		},
+	}
+}
+
+// ExampleGenerator generates tech demos under examples/.
+type ExampleGenerator struct {
+	baseGenerator
+}
+
+// NewExampleGenerator builds the Generator used by the "new example" subcommand.
+func NewExampleGenerator() *ExampleGenerator {
+	return &ExampleGenerator{
+		baseGenerator{
+			kind:                "examples",
+			singular:            "example",
+			mkdocsNavIndex:      examplesNavIndex,
+			dependabotDirPrefix: "/examples",
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ImageRef is a parsed, normalized `[registry/][namespace/]repository[:tag|@digest]`
+// OCI image reference, exposed to templates as .Image.Registry, .Image.Repository,
+// .Image.Tag and .Image.Digest.
+type ImageRef struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// These mirror the grammar in the OCI distribution spec (inherited from
+// docker/distribution's reference package): a domain component, a lowercase path
+// component allowing single separators, a loose tag, and an algorithm-prefixed
+// digest.
+const (
+	domainComponentPattern = `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+	domainPattern          = domainComponentPattern + `(?:\.` + domainComponentPattern + `)*(?::[0-9]+)?`
+	pathComponentPattern   = `[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*`
+	tagPattern             = `[\w][\w.-]{0,127}`
+	digestPattern          = `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}`
+)
+
+var (
+	domainRegexp = regexp.MustCompile(`^` + domainPattern + `$`)
+	pathRegexp   = regexp.MustCompile(`^` + pathComponentPattern + `(?:/` + pathComponentPattern + `)*$`)
+	tagRegexp    = regexp.MustCompile(`^` + tagPattern + `$`)
+	digestRegexp = regexp.MustCompile(`^` + digestPattern + `$`)
+)
+
+// isRegistry reports whether segment, the first "/"-separated piece of a reference,
+// names a registry host rather than the first component of a Docker Hub repository
+// path. This mirrors the static-prefix heuristic pkgsite's matchStatic uses for its
+// own path routing: a segment is only treated as a host if it looks like one
+// ("localhost", or containing a "." or a ":") since that is the one thing a bare
+// Docker Hub namespace (e.g. "library", "testcontainers") can never contain.
+func isRegistry(segment string) bool {
+	return segment == "localhost" || strings.ContainsAny(segment, ".:")
+}
+
+// ParseImageRef parses and validates ref, normalizing a missing registry/namespace
+// to "docker.io/library" and a missing tag to "latest".
+func ParseImageRef(ref string) (ImageRef, error) {
+	if ref == "" {
+		return ImageRef{}, fmt.Errorf("image ref must not be empty")
+	}
+
+	rest := ref
+	var tag, digest string
+
+	switch at := strings.LastIndex(rest, "@"); {
+	case at != -1:
+		digest = rest[at+1:]
+		rest = rest[:at]
+		if !digestRegexp.MatchString(digest) {
+			return ImageRef{}, fmt.Errorf("invalid digest %q in image ref %q", digest, ref)
+		}
+	default:
+		// A tag, if present, lives after the last ":" in the final path segment;
+		// a ":" before the last "/" is a registry port (e.g. localhost:5000/foo),
+		// not a tag separator.
+		if lastColon := strings.LastIndex(rest, ":"); lastColon > strings.LastIndex(rest, "/") {
+			tag = rest[lastColon+1:]
+			rest = rest[:lastColon]
+			if !tagRegexp.MatchString(tag) {
+				return ImageRef{}, fmt.Errorf("invalid tag %q in image ref %q", tag, ref)
+			}
+		}
+	}
+
+	registry := "docker.io"
+	segments := strings.Split(rest, "/")
+	if len(segments) > 1 && isRegistry(segments[0]) {
+		if !domainRegexp.MatchString(segments[0]) {
+			return ImageRef{}, fmt.Errorf("invalid registry %q in image ref %q", segments[0], ref)
+		}
+		registry = segments[0]
+		segments = segments[1:]
+	}
+
+	repository := strings.Join(segments, "/")
+	if repository == "" {
+		return ImageRef{}, fmt.Errorf("invalid image ref %q: missing repository", ref)
+	}
+	if !pathRegexp.MatchString(repository) {
+		return ImageRef{}, fmt.Errorf("invalid repository %q in image ref %q", repository, ref)
+	}
+
+	if registry == "docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	if tag == "" && digest == "" {
+		tag = "latest"
+		fmt.Printf("warning: image ref %q has no tag or digest, defaulting to %q\n", ref, registry+"/"+repository+":latest")
+	}
+
+	return ImageRef{Registry: registry, Repository: repository, Tag: tag, Digest: digest}, nil
+}
+
+// String renders the canonical, normalized reference, preferring the digest over
+// the tag when both are somehow set.
+func (r ImageRef) String() string {
+	if r.Digest != "" {
+		return r.Registry + "/" + r.Repository + "@" + r.Digest
+	}
+
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
+
+// Pinned renders the reference by digest instead of by tag, for the -pin-digest
+// example.go variant. It falls back to the tag-based form when no digest is known.
+func (r ImageRef) Pinned() string {
+	if r.Digest == "" {
+		return r.String()
+	}
+
+	return r.Registry + "/" + r.Repository + "@" + r.Digest
+}
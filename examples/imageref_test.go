@@ -0,0 +1,100 @@
+package main
+
+import "testing"
+
+// TestParseImageRef mirrors the table-driven style of pkgsite's TestMatchStatic:
+// each case asserts either a successful, normalized parse or a rejection.
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		want    ImageRef
+		wantErr bool
+	}{
+		{
+			name: "docker hub, no namespace, no tag",
+			ref:  "mongo",
+			want: ImageRef{Registry: "docker.io", Repository: "library/mongo", Tag: "latest"},
+		},
+		{
+			name: "docker hub, namespace and tag",
+			ref:  "testcontainers/ryuk:0.5.1",
+			want: ImageRef{Registry: "docker.io", Repository: "testcontainers/ryuk", Tag: "0.5.1"},
+		},
+		{
+			name: "ghcr.io",
+			ref:  "ghcr.io/testcontainers/example:latest",
+			want: ImageRef{Registry: "ghcr.io", Repository: "testcontainers/example", Tag: "latest"},
+		},
+		{
+			name: "quay.io",
+			ref:  "quay.io/minio/minio:RELEASE.2023-01-01T00-00-00Z",
+			want: ImageRef{Registry: "quay.io", Repository: "minio/minio", Tag: "RELEASE.2023-01-01T00-00-00Z"},
+		},
+		{
+			name: "gcr.io",
+			ref:  "gcr.io/google-containers/pause:3.9",
+			want: ImageRef{Registry: "gcr.io", Repository: "google-containers/pause", Tag: "3.9"},
+		},
+		{
+			name: "localhost with port, no tag",
+			ref:  "localhost:5000/myimage",
+			want: ImageRef{Registry: "localhost:5000", Repository: "myimage", Tag: "latest"},
+		},
+		{
+			name: "digest ref",
+			ref:  "mongo@sha256:4e8c2d1f6c3d77e4f3f3b2a29a1e1e6e4e1ad0b1ac0b0c1b4e4e2b7a6f6e4c3e",
+			want: ImageRef{Registry: "docker.io", Repository: "library/mongo", Digest: "sha256:4e8c2d1f6c3d77e4f3f3b2a29a1e1e6e4e1ad0b1ac0b0c1b4e4e2b7a6f6e4c3e"},
+		},
+		{
+			name: "registry and digest",
+			ref:  "ghcr.io/testcontainers/example@sha256:4e8c2d1f6c3d77e4f3f3b2a29a1e1e6e4e1ad0b1ac0b0c1b4e4e2b7a6f6e4c3e",
+			want: ImageRef{Registry: "ghcr.io", Repository: "testcontainers/example", Digest: "sha256:4e8c2d1f6c3d77e4f3f3b2a29a1e1e6e4e1ad0b1ac0b0c1b4e4e2b7a6f6e4c3e"},
+		},
+		{
+			name:    "empty ref",
+			ref:     "",
+			wantErr: true,
+		},
+		{
+			name:    "trailing colon with no tag",
+			ref:     "mongo:",
+			wantErr: true,
+		},
+		{
+			name:    "doubled slash",
+			ref:     "docker.io//library/mongo",
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag characters",
+			ref:     "mongo:!!!",
+			wantErr: true,
+		},
+		{
+			name:    "invalid digest",
+			ref:     "mongo@sha256:not-hex",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImageRef(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseImageRef(%q) = %+v, want error", tt.ref, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseImageRef(%q) unexpected error: %v", tt.ref, err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("ParseImageRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// imageFieldRegexp matches the `Image: "..."` field of a testcontainers.ContainerRequest
+// literal, which every generated example_test.go contains.
+var imageFieldRegexp = regexp.MustCompile(`Image:\s*"([^"]+)"`)
+
+// inferExampleFromTest reconstructs a best-effort Example for a module/example that
+// predates generated.toml, by reading its "<name>_test.go" file and extracting the
+// image reference from its Image field. TCVersion and any vars.toml variables aren't
+// recoverable this way and are left at their zero values.
+func inferExampleFromTest(exampleDir string, name string) (Example, error) {
+	path := filepath.Join(exampleDir, name+"_test.go")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Example{}, fmt.Errorf("no generated.toml, and could not read %s to infer one: %w", path, err)
+	}
+
+	match := imageFieldRegexp.FindSubmatch(content)
+	if match == nil {
+		return Example{}, fmt.Errorf("no generated.toml, and could not find an Image field in %s", path)
+	}
+
+	image, err := ParseImageRef(string(match[1]))
+	if err != nil {
+		return Example{}, fmt.Errorf("invalid image inferred from %s: %w", path, err)
+	}
+
+	return Example{
+		Image:     image,
+		Name:      name,
+		TitleName: cases.Title(language.Und, cases.NoLower).String(name),
+	}, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInferExampleFromTest(t *testing.T) {
+	dir := t.TempDir()
+
+	content := []byte(`package mongodb_test
+
+func TestMongoDB(t *testing.T) {
+	req := testcontainers.ContainerRequest{
+		Image: "mongo:6",
+	}
+	_ = req
+}
+`)
+
+	if err := os.WriteFile(filepath.Join(dir, "mongodb_test.go"), content, 0644); err != nil {
+		t.Fatalf("could not write fixture: %v", err)
+	}
+
+	example, err := inferExampleFromTest(dir, "mongodb")
+	if err != nil {
+		t.Fatalf("inferExampleFromTest() unexpected error: %v", err)
+	}
+
+	if got, want := example.Image.String(), "docker.io/library/mongo:6"; got != want {
+		t.Fatalf("inferExampleFromTest() image = %q, want %q", got, want)
+	}
+}
+
+func TestInferExampleFromTestMissingFile(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := inferExampleFromTest(dir, "mongodb"); err == nil {
+		t.Fatal("expected an error when the example_test.go file does not exist")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import "bytes"
+
+// Hand-written additions wrapped in a //testcontainers:generated-keep region
+// survive Verify's drift detection and its -fix rewrite: the region found in the
+// on-disk file is spliced back into the freshly rendered output before the two are
+// diffed. Generate does not apply them and overwrites the whole file.
+const (
+	keepRegionStart = "// testcontainers:generated-keep:start"
+	keepRegionEnd   = "// testcontainers:generated-keep:end"
+)
+
+// applyKeepRegions splices every testcontainers:generated-keep region found in
+// existing into the same position in rendered, so that hand-written additions
+// inside those regions are never reported as drift. If the template itself changed
+// the number of keep regions, rendered is returned unchanged since there is nothing
+// safe to splice.
+func applyKeepRegions(existing []byte, rendered []byte) []byte {
+	existingRegions := extractKeepRegions(existing)
+	if len(existingRegions) == 0 {
+		return rendered
+	}
+
+	renderedRegions := extractKeepRegions(rendered)
+	if len(renderedRegions) != len(existingRegions) {
+		return rendered
+	}
+
+	result := make([]byte, 0, len(rendered))
+	rest := rendered
+	for i, region := range renderedRegions {
+		idx := bytes.Index(rest, region)
+		result = append(result, rest[:idx]...)
+		result = append(result, existingRegions[i]...)
+		rest = rest[idx+len(region):]
+	}
+
+	return append(result, rest...)
+}
+
+// extractKeepRegions returns the full text of every testcontainers:generated-keep
+// region in content, markers included, in order.
+func extractKeepRegions(content []byte) [][]byte {
+	var regions [][]byte
+
+	start := []byte(keepRegionStart)
+	end := []byte(keepRegionEnd)
+
+	rest := content
+	for {
+		s := bytes.Index(rest, start)
+		if s == -1 {
+			break
+		}
+
+		e := bytes.Index(rest[s:], end)
+		if e == -1 {
+			break
+		}
+		e += s + len(end)
+
+		regions = append(regions, rest[s:e])
+		rest = rest[e:]
+	}
+
+	return regions
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestApplyKeepRegions(t *testing.T) {
+	existing := []byte("package foo\n\n// testcontainers:generated-keep:start\nfunc helper() {}\n// testcontainers:generated-keep:end\n\nfunc Generated() {}\n")
+	rendered := []byte("package foo\n\n// testcontainers:generated-keep:start\n// testcontainers:generated-keep:end\n\nfunc Generated() { /* changed */ }\n")
+
+	got := applyKeepRegions(existing, rendered)
+
+	if !bytes.Contains(got, []byte("func helper() {}")) {
+		t.Fatalf("keep region was not preserved: %s", got)
+	}
+	if !bytes.Contains(got, []byte("/* changed */")) {
+		t.Fatalf("content outside the keep region should still come from rendered: %s", got)
+	}
+}
+
+func TestApplyKeepRegionsNoRegion(t *testing.T) {
+	existing := []byte("package foo\n")
+	rendered := []byte("package bar\n")
+
+	got := applyKeepRegions(existing, rendered)
+	if !bytes.Equal(got, rendered) {
+		t.Fatalf("applyKeepRegions() = %q, want rendered unchanged %q", got, rendered)
+	}
+}
+
+func TestApplyKeepRegionsMismatchedCount(t *testing.T) {
+	existing := []byte("// testcontainers:generated-keep:start\nfoo\n// testcontainers:generated-keep:end\n")
+	rendered := []byte("no regions here\n")
+
+	got := applyKeepRegions(existing, rendered)
+	if !bytes.Equal(got, rendered) {
+		t.Fatalf("applyKeepRegions() with a mismatched region count should return rendered unchanged, got %q", got)
+	}
+}
@@ -1,40 +1,37 @@
 package main
 
 import (
-	"flag"
 	"fmt"
-	"html/template"
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/urfave/cli/v2"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
-var nameVar string
-var nameTitleVar string
-var imageVar string
-
 var templates = []string{
 	"ci.yml", "docs_example.md", "example_test.go", "example.go", "go.mod", "go.sum", "Makefile", "tools.go",
 }
 
-func init() {
-	flag.StringVar(&nameVar, "name", "", "Name of the example. Only alphabetical characters are allowed.")
-	flag.StringVar(&nameTitleVar, "title", "", "(Optional) Title of the example name, used to override the name in the case of mixed casing (Mongodb -> MongoDB). Use camel-case when needed. Only alphabetical characters are allowed.")
-	flag.StringVar(&imageVar, "image", "", "Fully-qualified name of the Docker image to be used by the example")
+// generators maps the "new"/"rm"/"list" subcommand kind argument ("module" or
+// "example") to the Generator that knows how to produce it.
+var generators = map[string]Generator{
+	"module":  NewModuleGenerator(),
+	"example": NewExampleGenerator(),
 }
 
 type Example struct {
-	Image     string // fully qualified name of the Docker image
+	Image     ImageRef // parsed, normalized reference of the Docker image
 	Name      string
-	TitleName string // title of the name: e.g. "mongodb" -> "MongoDB"
-	TCVersion string // Testcontainers for Go version
+	TitleName string            // title of the name: e.g. "mongodb" -> "MongoDB"
+	TCVersion string            // Testcontainers for Go version
+	Vars      map[string]string // extra variables declared by the template's vars.toml
+	PinDigest bool              // also render a pinned-by-digest usage example, via .Image.Pinned
 }
 
 func (e *Example) Lower() string {
@@ -67,191 +64,244 @@ func (e *Example) Validate() error {
 		return fmt.Errorf("invalid title: %s. Only alphabetical characters are allowed", e.TitleName)
 	}
 
-	return nil
-}
-
-func main() {
-	required := []string{"name", "image"}
-	flag.Parse()
-
-	seen := make(map[string]bool)
-	flag.Visit(func(f *flag.Flag) { seen[f.Name] = true })
-	for _, req := range required {
-		if !seen[req] {
-			// or possibly use `log.Fatalf` instead of:
-			fmt.Fprintf(os.Stderr, "missing required -%s argument/flag\n", req)
-			os.Exit(2) // the same exit code flag.Parse uses
-		}
+	if e.Image.Repository == "" {
+		return fmt.Errorf("invalid image: %s", e.Image)
 	}
 
-	examplesDir, err := filepath.Abs(filepath.Dir(nameVar))
-	if err != nil {
-		fmt.Printf(">> could not get the examples dir: %v\n", err)
-		os.Exit(1)
+	if e.PinDigest && e.Image.Digest == "" {
+		return fmt.Errorf("-pin-digest was set but image %s was not resolved to a digest: pass an @sha256:... ref", e.Image)
 	}
 
-	rootDir := filepath.Dir(examplesDir)
-
-	mkdocsConfig, err := readMkdocsConfig(rootDir)
-	if err != nil {
-		fmt.Printf(">> could not read MkDocs config: %v\n", err)
-		os.Exit(1)
-	}
+	return nil
+}
 
-	example := Example{
-		Image:     imageVar,
-		Name:      nameVar,
-		TitleName: nameTitleVar,
-		TCVersion: mkdocsConfig.Extra.LatestVersion,
+func main() {
+	app := &cli.App{
+		Name:  "modulegen",
+		Usage: "scaffold, verify and remove testcontainers-go modules and examples",
+		Commands: []*cli.Command{
+			newCommand(),
+			rmCommand(),
+			listCommand(),
+			verifyCommand(),
+		},
 	}
 
-	err = generate(example, rootDir)
-	if err != nil {
-		fmt.Printf(">> error generating the example: %v\n", err)
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
-func generate(example Example, rootDir string) error {
-	if err := example.Validate(); err != nil {
-		return err
-	}
-
-	githubWorkflowsDir := filepath.Join(rootDir, ".github", "workflows")
-	examplesDir := filepath.Join(rootDir, "examples")
-	docsDir := filepath.Join(rootDir, "docs", "examples")
-
-	funcMap := template.FuncMap{
-		"ToLower":      func() string { return example.Lower() },
-		"Title":        func() string { return example.Title() },
-		"ToLowerTitle": func() string { return example.LowerTitle() },
-		"codeinclude":  func(s string) template.HTML { return template.HTML(s) }, // escape HTML comments for codeinclude
-	}
-
-	// create the example dir
-	err := os.MkdirAll(examplesDir, 0700)
-	if err != nil {
-		return err
+// newCommand returns the "new" command, which has one subcommand per kind of
+// generator ("module", "example") so that each can declare its own flags.
+func newCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "new",
+		Usage: "scaffold a new module or example",
+		Subcommands: []*cli.Command{
+			newGenerateCommand("module", generators["module"]),
+			newGenerateCommand("example", generators["example"]),
+		},
 	}
+}
 
-	exampleLower := example.Lower()
-
-	for _, tmpl := range templates {
-		name := tmpl + ".tmpl"
-		t, err := template.New(name).Funcs(funcMap).ParseFiles(filepath.Join("_template", name))
-		if err != nil {
-			return err
-		}
-
-		// create a new file
-		var exampleFilePath string
-
-		if strings.EqualFold(tmpl, "docs_example.md") {
-			// docs example file will go into the docs directory
-			exampleFilePath = filepath.Join(docsDir, exampleLower+".md")
-		} else if strings.EqualFold(tmpl, "ci.yml") {
-			// GitHub workflow example file will go into the .github/workflows directory
-			exampleFilePath = filepath.Join(githubWorkflowsDir, exampleLower+"-example.yml")
-		} else if strings.EqualFold(tmpl, "tools.go") {
-			// tools.go example file will go into the tools package
-			exampleFilePath = filepath.Join(examplesDir, exampleLower, "tools", tmpl)
-		} else {
-			exampleFilePath = filepath.Join(examplesDir, exampleLower, strings.ReplaceAll(tmpl, "example", exampleLower))
-		}
-
-		err = os.MkdirAll(filepath.Dir(exampleFilePath), 0777)
-		if err != nil {
-			return err
-		}
-
-		exampleFile, _ := os.Create(exampleFilePath)
-		defer exampleFile.Close()
-
-		err = t.ExecuteTemplate(exampleFile, name, example)
-		if err != nil {
-			return err
-		}
+func newGenerateCommand(kind string, generator Generator) *cli.Command {
+	return &cli.Command{
+		Name:  kind,
+		Usage: fmt.Sprintf("scaffold a new %s", kind),
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "name", Required: true, Usage: fmt.Sprintf("Name of the %s. Only alphabetical characters are allowed.", kind)},
+			&cli.StringFlag{Name: "title", Usage: "(Optional) Title of the name, used to override the name in the case of mixed casing (Mongodb -> MongoDB). Use camel-case when needed. Only alphabetical characters are allowed."},
+			&cli.StringFlag{Name: "image", Required: true, Usage: fmt.Sprintf("Fully-qualified name of the Docker image to be used by the %s", kind)},
+			&cli.BoolFlag{Name: "non-interactive", Usage: "Fail instead of prompting when a template variable has no default and was not passed with -var."},
+			&cli.StringSliceFlag{Name: "var", Usage: "Set a template variable declared in vars.toml, as key=value. Can be repeated."},
+			&cli.BoolFlag{Name: "pin-digest", Usage: "Also render a pinned-by-digest usage example. Requires -image to include an @sha256:... digest."},
+		},
+		Action: func(c *cli.Context) error {
+			rootDir, err := rootDir()
+			if err != nil {
+				return err
+			}
+
+			mkdocsConfig, err := readMkdocsConfig(rootDir)
+			if err != nil {
+				return fmt.Errorf("could not read MkDocs config: %w", err)
+			}
+
+			cliVars, err := parseCliVars(c.StringSlice("var"))
+			if err != nil {
+				return err
+			}
+
+			templateVars, err := loadTemplateVars(generator.TemplateDir())
+			if err != nil {
+				return err
+			}
+
+			vars, err := resolveVars(templateVars, cliVars, c.Bool("non-interactive"))
+			if err != nil {
+				return err
+			}
+
+			image, err := ParseImageRef(c.String("image"))
+			if err != nil {
+				return fmt.Errorf("invalid -image: %w", err)
+			}
+
+			example := Example{
+				Image:     image,
+				Name:      c.String("name"),
+				TitleName: c.String("title"),
+				TCVersion: mkdocsConfig.Extra.LatestVersion,
+				Vars:      vars,
+				PinDigest: c.Bool("pin-digest"),
+			}
+
+			if err := generator.Generate(example, rootDir); err != nil {
+				return fmt.Errorf("error generating the %s: %w", kind, err)
+			}
+
+			return nil
+		},
 	}
+}
 
-	// update examples in mkdocs
-	err = generateMkdocs(rootDir, exampleLower)
-	if err != nil {
-		return err
+// rmCommand removes a previously generated module or example.
+func rmCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "rm",
+		Usage:     "remove a generated module or example",
+		ArgsUsage: "<module|example> <name>",
+		Action: func(c *cli.Context) error {
+			generator, name, err := generatorAndName(c)
+			if err != nil {
+				return err
+			}
+
+			rootDir, err := rootDir()
+			if err != nil {
+				return err
+			}
+
+			return generator.Remove(rootDir, name)
+		},
 	}
+}
 
-	// update examples in dependabot
-	err = generateDependabotUpdates(rootDir, exampleLower)
-	if err != nil {
-		return err
+// listCommand lists the modules or examples already generated.
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "list",
+		Usage:     "list generated modules or examples",
+		ArgsUsage: "<module|example>",
+		Action: func(c *cli.Context) error {
+			generator, ok := generators[c.Args().First()]
+			if !ok {
+				return fmt.Errorf("unknown kind %q: expected %q or %q", c.Args().First(), "module", "example")
+			}
+
+			rootDir, err := rootDir()
+			if err != nil {
+				return err
+			}
+
+			names, err := generator.List(rootDir)
+			if err != nil {
+				return err
+			}
+
+			for _, name := range names {
+				fmt.Println(name)
+			}
+
+			return nil
+		},
 	}
-
-	fmt.Println("Please go to", example.Lower(), "directory and execute 'go mod tidy' to synchronize the dependencies")
-	fmt.Println("Commit the modified files and submit a pull request to include them into the project")
-	fmt.Println("Thanks!")
-	return nil
 }
 
-func generateDependabotUpdates(rootDir string, exampleLower string) error {
-	// update examples in dependabot
-	dependabotConfig, err := readDependabotConfig(rootDir)
-	if err != nil {
-		return err
+// verifyCommand detects drift between the files already generated under modules/
+// and examples/ and what their templates would now produce, and fails CI when any
+// is found. With -fix, it rewrites the drifted files and regenerates the mkdocs nav
+// and dependabot config instead of failing.
+func verifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "verify",
+		Aliases: []string{"lint"},
+		Usage:   "detect drift between generated modules/examples and their templates",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "fix", Usage: "rewrite files, and regenerate mkdocs nav and dependabot config, to clear any drift found"},
+		},
+		Action: func(c *cli.Context) error {
+			rootDir, err := rootDir()
+			if err != nil {
+				return err
+			}
+
+			fix := c.Bool("fix")
+			drifted := false
+
+			for _, kind := range []string{"module", "example"} {
+				generator := generators[kind]
+
+				names, err := generator.List(rootDir)
+				if err != nil {
+					return err
+				}
+
+				for _, name := range names {
+					fileDrift, err := generator.Verify(rootDir, name, fix)
+					if err != nil {
+						return err
+					}
+					drifted = drifted || fileDrift
+				}
+
+				mkdocsDrift, err := generator.VerifyMkdocs(rootDir, fix)
+				if err != nil {
+					return err
+				}
+				drifted = drifted || mkdocsDrift
+
+				dependabotDrift, err := generator.VerifyDependabot(rootDir, fix)
+				if err != nil {
+					return err
+				}
+				drifted = drifted || dependabotDrift
+			}
+
+			if drifted && !fix {
+				return fmt.Errorf("generated files have drifted from their templates; run with -fix to rewrite them")
+			}
+
+			return nil
+		},
 	}
+}
 
-	dependabotExampleUpdates := dependabotConfig.Updates
-
-	// make sure the main module is the first element in the list of examples,
-	// the compose module is the second element
-	exampleUpdates := make(Updates, len(dependabotExampleUpdates)-2)
-	j := 0
+func generatorAndName(c *cli.Context) (Generator, string, error) {
+	kind := c.Args().Get(0)
+	name := c.Args().Get(1)
 
-	for _, exampleUpdate := range dependabotExampleUpdates {
-		// filter out the index.md file
-		if exampleUpdate.Directory != "/" && exampleUpdate.Directory != "/modules/compose" {
-			exampleUpdates[j] = exampleUpdate
-			j++
-		}
+	generator, ok := generators[kind]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown kind %q: expected %q or %q", kind, "module", "example")
 	}
 
-	exampleUpdates = append(exampleUpdates, NewUpdate(exampleLower))
-	sort.Sort(exampleUpdates)
-
-	// prepend the main and compose modules
-	exampleUpdates = append([]Update{dependabotExampleUpdates[0], dependabotExampleUpdates[1]}, exampleUpdates...)
-
-	dependabotConfig.Updates = exampleUpdates
+	if name == "" {
+		return nil, "", fmt.Errorf("missing name argument")
+	}
 
-	return writeDependabotConfig(rootDir, dependabotConfig)
+	return generator, name, nil
 }
 
-func generateMkdocs(rootDir string, exampleLower string) error {
-	// update examples in mkdocs
-	mkdocsConfig, err := readMkdocsConfig(rootDir)
+// rootDir returns the repository root, assuming this binary is always run from the
+// examples directory.
+func rootDir() (string, error) {
+	examplesDir, err := filepath.Abs(".")
 	if err != nil {
-		return err
-	}
-
-	mkdocsExamplesNav := mkdocsConfig.Nav[3].Examples
-
-	// make sure the index.md is the first element in the list of examples in the nav
-	examplesNav := make([]string, len(mkdocsExamplesNav)-1)
-	j := 0
-
-	for _, exampleNav := range mkdocsExamplesNav {
-		// filter out the index.md file
-		if !strings.HasSuffix(exampleNav, "index.md") {
-			examplesNav[j] = exampleNav
-			j++
-		}
+		return "", fmt.Errorf("could not get the examples dir: %w", err)
 	}
 
-	examplesNav = append(examplesNav, "examples/"+exampleLower+".md")
-	sort.Strings(examplesNav)
-
-	// prepend the index.md file
-	examplesNav = append([]string{"examples/index.md"}, examplesNav...)
-
-	mkdocsConfig.Nav[3].Examples = examplesNav
-
-	return writeMkdocsConfig(rootDir, mkdocsConfig)
+	return filepath.Dir(examplesDir), nil
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// stampFileName is the name of the per-example file that persists the inputs
+// Generate was called with, so that a later verify pass can reconstruct the
+// template context without asking the user again.
+const stampFileName = "generated.toml"
+
+// stamp is the on-disk representation of an Example, used to round-trip it through
+// generated.toml. Image is stored as its canonical ref string and re-parsed on read,
+// rather than as a nested table, so the stamp stays a flat, human-readable file.
+type stamp struct {
+	Image     string            `toml:"image"`
+	Name      string            `toml:"name"`
+	TitleName string            `toml:"title_name"`
+	TCVersion string            `toml:"tc_version"`
+	Vars      map[string]string `toml:"vars"`
+	PinDigest bool              `toml:"pin_digest"`
+}
+
+func (s stamp) example() (Example, error) {
+	image, err := ParseImageRef(s.Image)
+	if err != nil {
+		return Example{}, err
+	}
+
+	return Example{
+		Image:     image,
+		Name:      s.Name,
+		TitleName: s.TitleName,
+		TCVersion: s.TCVersion,
+		Vars:      s.Vars,
+		PinDigest: s.PinDigest,
+	}, nil
+}
+
+// writeStamp persists example's inputs to dir/generated.toml.
+func writeStamp(dir string, example Example) error {
+	f, err := os.Create(filepath.Join(dir, stampFileName))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return toml.NewEncoder(f).Encode(stamp{
+		Image:     example.Image.String(),
+		Name:      example.Name,
+		TitleName: example.TitleName,
+		TCVersion: example.TCVersion,
+		Vars:      example.Vars,
+		PinDigest: example.PinDigest,
+	})
+}
+
+// readStamp reconstructs the Example that generated the integration in dir, if it
+// was stamped. found is false, with a nil error, if dir predates generated.toml
+// being written; callers should fall back to inferExampleFromTest in that case.
+func readStamp(dir string) (example Example, found bool, err error) {
+	path := filepath.Join(dir, stampFileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return Example{}, false, nil
+	}
+
+	var s stamp
+	if _, err := toml.DecodeFile(path, &s); err != nil {
+		return Example{}, false, err
+	}
+
+	example, err = s.example()
+	return example, true, err
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// wordBoundary matches the lower-to-upper transition in a camelCase or PascalCase
+// identifier, so it can be split alongside existing "_" and "-" separators.
+var wordBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// splitWords breaks s into its constituent words, regardless of whether it was
+// originally camelCase, PascalCase, snake_case or kebab-case.
+func splitWords(s string) []string {
+	s = wordBoundary.ReplaceAllString(s, "$1 $2")
+	s = strings.NewReplacer("_", " ", "-", " ").Replace(s)
+	return strings.Fields(s)
+}
+
+func toSnakeCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "_")
+}
+
+func toKebabCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "-")
+}
+
+func toCamelCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	for i := 1; i < len(words); i++ {
+		words[i] = cases.Title(language.Und, cases.NoLower).String(words[i])
+	}
+	return strings.Join(words, "")
+}
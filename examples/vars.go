@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// templateVar is one entry of a _template/<kind>/vars.toml file: an extra,
+// template-specific variable that is prompted for (or taken from -var/its default)
+// on top of the built-in Example fields.
+type templateVar struct {
+	Name      string `toml:"name"`
+	Prompt    string `toml:"prompt"`
+	Help      string `toml:"help"`
+	Default   string `toml:"default"`
+	DependsOn string `toml:"depends_on"`
+}
+
+// templateVars is the parsed contents of a vars.toml file.
+type templateVars struct {
+	Var []templateVar `toml:"var"`
+}
+
+// loadTemplateVars reads templateDir/vars.toml, if it exists. A missing file means
+// the template declares no extra variables, which is not an error.
+func loadTemplateVars(templateDir string) (templateVars, error) {
+	var vars templateVars
+
+	path := filepath.Join(templateDir, "vars.toml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return vars, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &vars); err != nil {
+		return vars, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	return vars, nil
+}
+
+// resolveVars answers every variable declared by vars, in declaration order. A
+// var's depends_on must name an earlier-declared var, or resolveVars errors; a var
+// whose depends_on was itself left unanswered (empty) is skipped entirely, since it
+// doesn't apply. cliVars takes priority over prompting; defaults may reference
+// environment variables ($USER) and previously-answered prompts
+// (${TMPL_PROMPT_NAME}). In non-interactive mode, any variable left without a value
+// after cliVars and its default is an error, unless it's a gate (something else's
+// depends_on) with nothing depending on it having answered yet — such a gate simply
+// resolves to "", which causes its dependents to be skipped above.
+func resolveVars(vars templateVars, cliVars map[string]string, nonInteractive bool) (map[string]string, error) {
+	answers := make(map[string]string, len(vars.Var))
+	declared := make(map[string]bool, len(vars.Var))
+
+	gates := make(map[string]bool, len(vars.Var))
+	for _, v := range vars.Var {
+		if v.DependsOn != "" {
+			gates[v.DependsOn] = true
+		}
+	}
+
+	for _, v := range vars.Var {
+		if v.DependsOn != "" && !declared[v.DependsOn] {
+			return nil, fmt.Errorf("var %q declares depends_on %q, which must be declared earlier in vars.toml", v.Name, v.DependsOn)
+		}
+		declared[v.Name] = true
+
+		if v.DependsOn != "" && answers[v.DependsOn] == "" {
+			continue
+		}
+
+		if val, ok := cliVars[v.Name]; ok {
+			answers[v.Name] = val
+			continue
+		}
+
+		def := expandDefault(v.Default, answers)
+
+		if nonInteractive {
+			if def == "" && !gates[v.Name] {
+				return nil, fmt.Errorf("missing required -var %s: no default and -non-interactive was set", v.Name)
+			}
+			answers[v.Name] = def
+			continue
+		}
+
+		answers[v.Name] = promptForVar(v, def)
+	}
+
+	return answers, nil
+}
+
+// expandDefault expands $ENV_VAR references and ${TMPL_PROMPT_NAME} references to
+// previously-answered variables within a vars.toml default.
+func expandDefault(def string, answers map[string]string) string {
+	def = os.Expand(def, func(name string) string {
+		const promptPrefix = "TMPL_PROMPT_"
+		if strings.HasPrefix(name, promptPrefix) {
+			for varName, value := range answers {
+				if strings.EqualFold(varName, strings.TrimPrefix(name, promptPrefix)) {
+					return value
+				}
+			}
+			return ""
+		}
+
+		return os.Getenv(name)
+	})
+
+	return def
+}
+
+// promptForVar prompts the user for v on stdin, showing its help text and default,
+// and returns the default unchanged if the user enters nothing.
+func promptForVar(v templateVar, def string) string {
+	if v.Help != "" {
+		fmt.Println(v.Help)
+	}
+
+	prompt := v.Prompt
+	if prompt == "" {
+		prompt = v.Name
+	}
+
+	if def != "" {
+		fmt.Printf("%s [%s]: ", prompt, def)
+	} else {
+		fmt.Printf("%s: ", prompt)
+	}
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def
+	}
+
+	return line
+}
+
+// parseCliVars turns a list of "-var key=value" flag values into a map.
+func parseCliVars(raw []string) (map[string]string, error) {
+	vars := make(map[string]string, len(raw))
+
+	for _, kv := range raw {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -var %q: expected key=value", kv)
+		}
+		vars[name] = value
+	}
+
+	return vars, nil
+}
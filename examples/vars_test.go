@@ -0,0 +1,77 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandDefault(t *testing.T) {
+	t.Setenv("TESTCONTAINERS_MODGEN_TEST_VAR", "alice")
+
+	answers := map[string]string{"name": "mongodb"}
+
+	got := expandDefault("hello-$TESTCONTAINERS_MODGEN_TEST_VAR-${TMPL_PROMPT_NAME}", answers)
+	want := "hello-alice-mongodb"
+
+	if got != want {
+		t.Fatalf("expandDefault() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveVarsNonInteractive(t *testing.T) {
+	vars := templateVars{Var: []templateVar{
+		{Name: "port", Default: "8080"},
+		{Name: "healthcheck"},
+	}}
+
+	if _, err := resolveVars(vars, nil, true); err == nil {
+		t.Fatal("expected an error for a variable with no default and no -var in non-interactive mode")
+	}
+
+	vars.Var[1].Default = "/health"
+
+	got, err := resolveVars(vars, map[string]string{"port": "9090"}, true)
+	if err != nil {
+		t.Fatalf("resolveVars() unexpected error: %v", err)
+	}
+
+	want := map[string]string{"port": "9090", "healthcheck": "/health"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolveVars() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveVarsDependsOn(t *testing.T) {
+	vars := templateVars{Var: []templateVar{
+		{Name: "enableAuth", Default: ""},
+		{Name: "authUser", DependsOn: "enableAuth", Default: "admin"},
+	}}
+
+	got, err := resolveVars(vars, nil, true)
+	if err != nil {
+		t.Fatalf("resolveVars() unexpected error: %v", err)
+	}
+	if _, ok := got["authUser"]; ok {
+		t.Fatalf("authUser should have been skipped since enableAuth was left empty, got %v", got)
+	}
+
+	vars.Var[0].Default = "true"
+
+	got, err = resolveVars(vars, nil, true)
+	if err != nil {
+		t.Fatalf("resolveVars() unexpected error: %v", err)
+	}
+	if got["authUser"] != "admin" {
+		t.Fatalf("authUser should have resolved once enableAuth was answered, got %v", got)
+	}
+}
+
+func TestResolveVarsDependsOnUndeclared(t *testing.T) {
+	vars := templateVars{Var: []templateVar{
+		{Name: "authUser", DependsOn: "enableAuth", Default: "admin"},
+	}}
+
+	if _, err := resolveVars(vars, nil, true); err == nil {
+		t.Fatal("expected an error for depends_on referencing an undeclared var")
+	}
+}